@@ -0,0 +1,219 @@
+// Package filemode defines the different file modes supported by git and its
+// translation from and to the different representations
+package filemode
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+)
+
+// A FileMode represents the kind of tree entries used by git. It
+// resembles regular file systems modes, although it is much simpler as
+// it only used to distinguish regular files, symlinks, directories and
+// gitlinks.
+type FileMode int32
+
+const (
+	// Empty is used as the FileMode of tree elements when comparing
+	// against "the absence of a file".
+	Empty FileMode = 0
+	// Dir represent a Tree
+	Dir FileMode = 0040000
+	// Regular represent non-executable files. Notice that FileModes of Git
+	// link to this one (they are converted to this FileMode).
+	Regular FileMode = 0100644
+	// Deprecated represents non-executable files with the group writable bit
+	// set. This mode was deprecated since the earliest history of Git but
+	// still retained because it was created by some old Git versions, which
+	// are still in use and could create some files with this mode.
+	Deprecated FileMode = 0100664
+	// Executable represents executable files.
+	Executable FileMode = 0100755
+	// Symlink represents symbolic links to files.
+	Symlink FileMode = 0120000
+	// Submodule represents git submodules. This is a reference to another
+	// repository and its working tree is not directly accessible.
+	Submodule FileMode = 0160000
+)
+
+// Option configures the behavior of New.
+type Option func(*options)
+
+type options struct {
+	strict bool
+}
+
+// Strict makes New reject any input that is not the canonical 7-digit,
+// zero-padded octal representation produced by FileMode.String, such as
+// the shorthand "42" or the over-long "00000000000100644" that New
+// otherwise accepts for historical reasons.
+func Strict() Option {
+	return func(o *options) { o.strict = true }
+}
+
+// New takes the octal string representation of a FileMode and returns the
+// FileMode and a nil error. If the string can not be parsed to a 32 bit
+// unsigned octal number it returns Empty and the parsing error.
+//
+// Example: "40000" means Dir, "100644" means Regular.
+//
+// New is the inverse function of String() except for malformed or
+// non-canonical octal representations; pass Strict() to reject those too.
+func New(s string, opts ...Option) (FileMode, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.strict && len(s) != 7 {
+		return Empty, fmt.Errorf("not a canonical file mode representation: %q", s)
+	}
+
+	n, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return Empty, err
+	}
+
+	return FileMode(uint32(n)), nil
+}
+
+// NewFromFSFileMode returns the FileMode used by git to represent the
+// provided fs.FileMode, or an error if there is no equivalent FileMode
+// (for instance, there is no way to represent a named pipe, a socket, a
+// device, a char device, a temporary file or an irregular file in git).
+//
+// NewFromOSFileMode and NewFromFileInfo both defer to it;
+// NewFromOSFileModeWithPolicy applies its own Policy-driven handling.
+func NewFromFSFileMode(m fs.FileMode) (FileMode, error) {
+	if m.IsDir() {
+		return Dir, nil
+	}
+
+	switch {
+	case m&fs.ModeSymlink != 0:
+		return Symlink, nil
+	case m&fs.ModeNamedPipe != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	case m&fs.ModeSocket != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	case m&fs.ModeDevice != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	case m&fs.ModeCharDevice != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	case m&fs.ModeTemporary != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	case m&fs.ModeIrregular != 0:
+		return Empty, ErrUnsupportedFileMode(m)
+	}
+
+	if isExecutable(m) {
+		return Executable, nil
+	}
+
+	return Regular, nil
+}
+
+// NewFromOSFileMode returns the FileMode used by git to represent the
+// provided os.FileMode, or an error if there is no equivalent FileMode
+// (for instance, there is no way to represent a named pipe, a socket, a
+// device, a char device or a temporary file in git).
+//
+// It is a thin wrapper over NewFromFSFileMode kept so that code compiled
+// against os.FileMode keeps working unchanged.
+func NewFromOSFileMode(m os.FileMode) (FileMode, error) {
+	return NewFromFSFileMode(fs.FileMode(m))
+}
+
+// NewFromFileInfo returns the FileMode used by git to represent the mode
+// reported by the given fs.FileInfo, as returned by fs.FS implementations
+// (including in-memory worktrees) as well as os.Stat and os.Lstat.
+func NewFromFileInfo(fi fs.FileInfo) (FileMode, error) {
+	return NewFromFSFileMode(fi.Mode())
+}
+
+func isExecutable(m fs.FileMode) bool {
+	return m&0111 != 0
+}
+
+// ErrUnsupportedFileMode is returned when a fs.FileMode has no equivalent
+// git FileMode, for instance named pipes, sockets, devices, temporary and
+// irregular files.
+type ErrUnsupportedFileMode fs.FileMode
+
+func (e ErrUnsupportedFileMode) Error() string {
+	return fmt.Sprintf("no equivalent file mode: %s", fs.FileMode(e).String())
+}
+
+// Bytes returns the little-endian 32-bit representation of the FileMode,
+// as used by the packfile and the index encodings.
+func (m FileMode) Bytes() []byte {
+	a := make([]byte, 4)
+	binary.LittleEndian.PutUint32(a, uint32(m))
+	return a
+}
+
+// IsMalformed returns if the FileMode is not one of the FileModes supported
+// by git: Dir, Regular, Deprecated, Executable, Symlink or Submodule. Empty
+// is also considered malformed, since it only stands for the absence of a
+// file and is never a valid mode for one that exists.
+func (m FileMode) IsMalformed() bool {
+	return m != Dir &&
+		m != Regular &&
+		m != Deprecated &&
+		m != Executable &&
+		m != Symlink &&
+		m != Submodule
+}
+
+// String returns the zero-padded 7-digit octal representation of the
+// FileMode, as used by the tree object encoding.
+func (m FileMode) String() string {
+	return fmt.Sprintf("%07o", uint32(m))
+}
+
+// IsRegular returns if the FileMode represents that of a regular file,
+// that is, Regular or Deprecated. Note that this method does not check
+// for symlinks, executables or submodules.
+func (m FileMode) IsRegular() bool {
+	return m == Regular || m == Deprecated
+}
+
+// IsFile returns if the FileMode represents that of a file, that is, a
+// FileMode that is not a directory or a submodule: Regular, Deprecated,
+// Executable or Symlink.
+func (m FileMode) IsFile() bool {
+	return m == Regular ||
+		m == Deprecated ||
+		m == Executable ||
+		m == Symlink
+}
+
+// ToFSFileMode returns the fs.FileMode to be used when creating file system
+// elements with the given FileMode.
+func (m FileMode) ToFSFileMode() (fs.FileMode, error) {
+	switch m {
+	case Dir, Submodule:
+		return fs.ModePerm | fs.ModeDir, nil
+	case Regular, Deprecated:
+		return fs.FileMode(0644), nil
+	case Executable:
+		return fs.FileMode(0755), nil
+	case Symlink:
+		return fs.ModePerm | fs.ModeSymlink, nil
+	}
+
+	return fs.FileMode(0), fmt.Errorf("malformed mode (%s)", m)
+}
+
+// ToOSFileMode returns the os.FileMode to be used when creating file system
+// elements with the given FileMode.
+//
+// It is a thin wrapper over ToFSFileMode kept so that code compiled
+// against os.FileMode keeps working unchanged.
+func (m FileMode) ToOSFileMode() (os.FileMode, error) {
+	fm, err := m.ToFSFileMode()
+	return os.FileMode(fm), err
+}
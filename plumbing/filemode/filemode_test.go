@@ -2,8 +2,10 @@ package filemode
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -202,6 +204,76 @@ func (s *ModeSuite) TestNewFromOsFileModeSticky() {
 	}.test(s)
 }
 
+// fixtures for testing NewFromFSFileMode
+type fsFixture struct {
+	input    fs.FileMode
+	expected FileMode
+	err      string // error substring, empty string for nil error
+}
+
+func (f fsFixture) test(s *ModeSuite) {
+	obtained, err := NewFromFSFileMode(f.input)
+	comment := fmt.Sprintf("input = %s (%07o)", f.input, uint32(f.input))
+	s.Equal(f.expected, obtained, comment)
+	if f.err != "" {
+		s.ErrorContains(err, f.err, comment)
+	} else {
+		s.NoError(err, comment)
+	}
+}
+
+func (s *ModeSuite) TestNewFromFSFileModeSimplePerms() {
+	for _, f := range [...]fsFixture{
+		{fs.FileMode(0755) | fs.ModeDir, Dir, ""},
+		{fs.FileMode(0644), Regular, ""},
+		{fs.FileMode(0755), Executable, ""},
+		{fs.FileMode(0777) | fs.ModeSymlink, Symlink, ""},
+	} {
+		f.test(s)
+	}
+}
+
+func (s *ModeSuite) TestNewFromFSFileModeIrregular() {
+	// irregular files (fs.ModeIrregular has no os.FileMode counterpart in
+	// older code paths) have no git equivalent
+	fsFixture{
+		input:    fs.FileMode(0644) | fs.ModeIrregular,
+		expected: Empty, err: "no equivalent",
+	}.test(s)
+}
+
+// mockFileInfo is a minimal fs.FileInfo used to exercise NewFromFileInfo
+// without touching the real file system.
+type mockFileInfo struct {
+	mode fs.FileMode
+}
+
+func (m mockFileInfo) Name() string       { return "mock" }
+func (m mockFileInfo) Size() int64        { return 0 }
+func (m mockFileInfo) Mode() fs.FileMode  { return m.mode }
+func (m mockFileInfo) ModTime() time.Time { return time.Time{} }
+func (m mockFileInfo) IsDir() bool        { return m.mode.IsDir() }
+func (m mockFileInfo) Sys() interface{}   { return nil }
+
+func (s *ModeSuite) TestNewFromFileInfo() {
+	for _, f := range [...]fsFixture{
+		{fs.FileMode(0755) | fs.ModeDir, Dir, ""},
+		{fs.FileMode(0644), Regular, ""},
+		{fs.FileMode(0755), Executable, ""},
+		{fs.FileMode(0777) | fs.ModeSymlink, Symlink, ""},
+		{fs.FileMode(0644) | fs.ModeIrregular, Empty, "no equivalent"},
+	} {
+		obtained, err := NewFromFileInfo(mockFileInfo{mode: f.input})
+		comment := fmt.Sprintf("input = %s", f.input)
+		s.Equal(f.expected, obtained, comment)
+		if f.err != "" {
+			s.ErrorContains(err, f.err, comment)
+		} else {
+			s.NoError(err, comment)
+		}
+	}
+}
+
 func (s *ModeSuite) TestByte() {
 	for _, test := range [...]struct {
 		input    FileMode
@@ -349,3 +421,30 @@ func (s *ModeSuite) TestToOSFileMode() {
 		}
 	}
 }
+
+func (s *ModeSuite) TestToFSFileMode() {
+	for _, test := range [...]struct {
+		input     FileMode
+		expected  fs.FileMode
+		errRegExp string // empty string for nil error
+	}{
+		{Empty, fs.FileMode(0), "malformed"},
+		{Dir, fs.ModePerm | fs.ModeDir, ""},
+		{Regular, fs.FileMode(0644), ""},
+		{Deprecated, fs.FileMode(0644), ""},
+		{Executable, fs.FileMode(0755), ""},
+		{Symlink, fs.ModePerm | fs.ModeSymlink, ""},
+		{Submodule, fs.ModePerm | fs.ModeDir, ""},
+		{FileMode(01), fs.FileMode(0), "malformed"},
+	} {
+		obtained, err := test.input.ToFSFileMode()
+		comment := fmt.Sprintf("input = %s", test.input)
+		if test.errRegExp != "" {
+			s.Equal(fs.FileMode(0), obtained, comment)
+			s.ErrorContains(err, test.errRegExp, comment)
+		} else {
+			s.Equal(test.expected, obtained, comment)
+			s.NoError(err, comment)
+		}
+	}
+}
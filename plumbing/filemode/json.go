@@ -0,0 +1,76 @@
+package filemode
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+)
+
+// FromBytes is the inverse of Bytes: it decodes the little-endian 32-bit
+// representation of a FileMode, as used by the packfile and the index
+// encodings.
+func FromBytes(b []byte) (FileMode, error) {
+	if len(b) != 4 {
+		return Empty, fmt.Errorf("invalid length for FileMode: %d", len(b))
+	}
+
+	return FileMode(binary.LittleEndian.Uint32(b)), nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the same
+// 7-digit octal representation as String.
+func (m FileMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using New, so it
+// accepts the same non-canonical representations New does.
+func (m *FileMode) UnmarshalText(text []byte) error {
+	parsed, err := New(string(text))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, returning the same
+// little-endian representation as Bytes.
+func (m FileMode) MarshalBinary() ([]byte, error) {
+	return m.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using FromBytes.
+func (m *FileMode) UnmarshalBinary(data []byte) error {
+	parsed, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding the FileMode as the
+// same quoted 7-digit octal string produced by String, so that it reads
+// naturally in a JSON tree dump alongside hashes and paths.
+func (m FileMode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler using New.
+func (m *FileMode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := New(s)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,86 @@
+package filemode
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JSONSuite struct {
+	suite.Suite
+}
+
+func TestJSONSuite(t *testing.T) {
+	suite.Run(t, new(JSONSuite))
+}
+
+var (
+	_ encoding.TextMarshaler   = Regular
+	_ encoding.TextUnmarshaler = (*FileMode)(nil)
+	_ encoding.BinaryMarshaler = Regular
+	_ json.Marshaler           = Regular
+	_ json.Unmarshaler         = (*FileMode)(nil)
+)
+
+func (s *JSONSuite) TestJSONRoundTrip() {
+	for _, mode := range [...]FileMode{Empty, Dir, Regular, Deprecated, Executable, Symlink, Submodule} {
+		data, err := json.Marshal(mode)
+		s.NoError(err)
+		s.Equal(fmt.Sprintf("%q", mode.String()), string(data))
+
+		var obtained FileMode
+		s.NoError(json.Unmarshal(data, &obtained))
+		s.Equal(mode, obtained)
+	}
+}
+
+func (s *JSONSuite) TestTextRoundTrip() {
+	for _, mode := range [...]FileMode{Empty, Dir, Regular, Deprecated, Executable, Symlink, Submodule} {
+		text, err := mode.MarshalText()
+		s.NoError(err)
+		s.Equal(mode.String(), string(text))
+
+		var obtained FileMode
+		s.NoError(obtained.UnmarshalText(text))
+		s.Equal(mode, obtained)
+	}
+}
+
+func (s *JSONSuite) TestBinaryRoundTrip() {
+	for _, mode := range [...]FileMode{Empty, Dir, Regular, Deprecated, Executable, Symlink, Submodule} {
+		data, err := mode.MarshalBinary()
+		s.NoError(err)
+		s.Equal(mode.Bytes(), data)
+
+		var obtained FileMode
+		s.NoError(obtained.UnmarshalBinary(data))
+		s.Equal(mode, obtained)
+	}
+}
+
+func (s *JSONSuite) TestFromBytesInvalidLength() {
+	_, err := FromBytes([]byte{0x01, 0x02})
+	s.Error(err)
+}
+
+func (s *JSONSuite) TestNewStrict() {
+	for _, test := range [...]struct {
+		input string
+		ok    bool
+	}{
+		{"0100644", true},
+		{"42", false},
+		{"00000000000100644", false},
+	} {
+		_, err := New(test.input, Strict())
+		comment := fmt.Sprintf("input = %q", test.input)
+		if test.ok {
+			s.NoError(err, comment)
+		} else {
+			s.Error(err, comment)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package filemode
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+// PolicyAction determines how NewFromOSFileModeWithPolicy handles an
+// os.FileMode that has no direct git equivalent: named pipes, sockets,
+// devices, char devices, temporary and irregular files.
+type PolicyAction int
+
+const (
+	// PolicyError returns an ErrUnsupportedFileMode, exactly like
+	// NewFromOSFileMode. It is the zero value, so a zero Policy preserves
+	// this package's historical behavior.
+	PolicyError PolicyAction = iota
+	// PolicyCoerce maps the file to Regular or Executable based on its
+	// permission bits, ignoring the irregular mode bits entirely.
+	PolicyCoerce
+	// PolicySkip returns Empty and ErrSkip, letting the caller silently
+	// skip the file instead of failing the whole operation.
+	PolicySkip
+	// PolicyWarn behaves like PolicyCoerce, but first reports the
+	// decision through the Policy's Logger, if any.
+	PolicyWarn
+)
+
+// ErrSkip is returned by NewFromOSFileModeWithPolicy when the applicable
+// PolicyAction is PolicySkip.
+var ErrSkip = errors.New("file mode has no git equivalent, skipping")
+
+// PolicyLogger is the subset of *log.Logger used to report PolicyWarn
+// decisions.
+type PolicyLogger interface {
+	Printf(format string, args ...interface{})
+}
+
+// Policy controls how NewFromOSFileModeWithPolicy handles the kinds of
+// os.FileMode that have no git equivalent. Every field defaults to
+// PolicyError, so the zero Policy behaves exactly like NewFromOSFileMode.
+type Policy struct {
+	NamedPipe  PolicyAction
+	Socket     PolicyAction
+	Device     PolicyAction
+	CharDevice PolicyAction
+	Temporary  PolicyAction
+	Irregular  PolicyAction
+
+	// Logger receives one message per PolicyWarn decision. It may be left
+	// nil, in which case PolicyWarn behaves exactly like PolicyCoerce.
+	Logger PolicyLogger
+}
+
+// DefaultPolicy rejects every irregular mode with an error, giving the same
+// results as NewFromOSFileMode.
+var DefaultPolicy = Policy{}
+
+// NewFromOSFileModeWithPolicy is like NewFromOSFileMode, but lets the
+// caller decide, kind by kind, whether named pipes, sockets, devices,
+// char devices, temporary and irregular files should be rejected,
+// coerced to Regular/Executable, skipped, or coerced with a warning.
+//
+// This lets higher level packages (worktree, plumbing/format/index)
+// express staging semantics such as "treat FIFOs as empty regular files"
+// without duplicating switch statements over os.Mode* bits.
+func NewFromOSFileModeWithPolicy(m os.FileMode, policy Policy) (FileMode, error) {
+	if m.IsDir() {
+		return Dir, nil
+	}
+
+	if m&os.ModeSymlink != 0 {
+		return Symlink, nil
+	}
+
+	action, kind, irregular := policy.actionFor(m)
+	if !irregular {
+		return coerce(m), nil
+	}
+
+	switch action {
+	case PolicyCoerce:
+		return coerce(m), nil
+	case PolicySkip:
+		return Empty, ErrSkip
+	case PolicyWarn:
+		if policy.Logger != nil {
+			policy.Logger.Printf("filemode: coercing %s file mode %s", kind, m)
+		}
+		return coerce(m), nil
+	default:
+		return Empty, ErrUnsupportedFileMode(m)
+	}
+}
+
+// actionFor reports the PolicyAction and human readable kind for m, and
+// whether m is one of the irregular kinds the Policy governs at all.
+func (p Policy) actionFor(m os.FileMode) (action PolicyAction, kind string, irregular bool) {
+	switch {
+	case m&os.ModeNamedPipe != 0:
+		return p.NamedPipe, "named pipe", true
+	case m&os.ModeSocket != 0:
+		return p.Socket, "socket", true
+	case m&os.ModeCharDevice != 0:
+		return p.CharDevice, "char device", true
+	case m&os.ModeDevice != 0:
+		return p.Device, "device", true
+	case m&os.ModeTemporary != 0:
+		return p.Temporary, "temporary", true
+	case m&fs.ModeIrregular != 0:
+		return p.Irregular, "irregular", true
+	}
+
+	return PolicyError, "", false
+}
+
+// coerce maps m to Regular or Executable based solely on its permission
+// bits, disregarding any irregular mode bits it may also carry.
+func coerce(m os.FileMode) FileMode {
+	if isExecutable(fs.FileMode(m)) {
+		return Executable
+	}
+
+	return Regular
+}
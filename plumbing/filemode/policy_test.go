@@ -0,0 +1,102 @@
+package filemode
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicySuite struct {
+	suite.Suite
+}
+
+func TestPolicySuite(t *testing.T) {
+	suite.Run(t, new(PolicySuite))
+}
+
+func (s *PolicySuite) TestDefaultPolicyMatchesNewFromOSFileMode() {
+	for _, m := range [...]os.FileMode{
+		os.FileMode(0644) | os.ModeNamedPipe,
+		os.FileMode(0644) | os.ModeSocket,
+		os.FileMode(0644) | os.ModeDevice,
+		os.FileMode(0644) | os.ModeCharDevice,
+		os.FileMode(0644) | os.ModeTemporary,
+	} {
+		expected, expectedErr := NewFromOSFileMode(m)
+		obtained, err := NewFromOSFileModeWithPolicy(m, DefaultPolicy)
+		comment := fmt.Sprintf("input = %s", m)
+		s.Equal(expected, obtained, comment)
+		s.Equal(expectedErr, err, comment)
+	}
+}
+
+func (s *PolicySuite) TestPolicyCoerce() {
+	for _, test := range [...]struct {
+		input    os.FileMode
+		expected FileMode
+	}{
+		{os.FileMode(0644) | os.ModeNamedPipe, Regular},
+		{os.FileMode(0755) | os.ModeNamedPipe, Executable},
+		{os.FileMode(0644) | os.ModeSocket, Regular},
+		{os.FileMode(0644) | os.ModeDevice, Regular},
+		{os.FileMode(0644) | os.ModeCharDevice, Regular},
+		{os.FileMode(0644) | os.ModeTemporary, Regular},
+	} {
+		policy := Policy{
+			NamedPipe:  PolicyCoerce,
+			Socket:     PolicyCoerce,
+			Device:     PolicyCoerce,
+			CharDevice: PolicyCoerce,
+			Temporary:  PolicyCoerce,
+		}
+		obtained, err := NewFromOSFileModeWithPolicy(test.input, policy)
+		comment := fmt.Sprintf("input = %s", test.input)
+		s.NoError(err, comment)
+		s.Equal(test.expected, obtained, comment)
+	}
+}
+
+func (s *PolicySuite) TestPolicySkip() {
+	policy := Policy{NamedPipe: PolicySkip}
+	obtained, err := NewFromOSFileModeWithPolicy(os.FileMode(0644)|os.ModeNamedPipe, policy)
+	s.Equal(Empty, obtained)
+	s.ErrorIs(err, ErrSkip)
+}
+
+type recordingLogger struct {
+	messages []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...interface{}) {
+	l.messages = append(l.messages, fmt.Sprintf(format, args...))
+}
+
+func (s *PolicySuite) TestPolicyWarn() {
+	logger := &recordingLogger{}
+	policy := Policy{NamedPipe: PolicyWarn, Logger: logger}
+
+	obtained, err := NewFromOSFileModeWithPolicy(os.FileMode(0644)|os.ModeNamedPipe, policy)
+	s.NoError(err)
+	s.Equal(Regular, obtained)
+	s.Len(logger.messages, 1)
+}
+
+func (s *PolicySuite) TestPolicyWarnWithoutLogger() {
+	policy := Policy{NamedPipe: PolicyWarn}
+	obtained, err := NewFromOSFileModeWithPolicy(os.FileMode(0755)|os.ModeNamedPipe, policy)
+	s.NoError(err)
+	s.Equal(Executable, obtained)
+}
+
+func (s *PolicySuite) TestPolicyDirAndSymlinkIgnorePolicy() {
+	policy := Policy{}
+	obtained, err := NewFromOSFileModeWithPolicy(os.FileMode(0755)|os.ModeDir, policy)
+	s.NoError(err)
+	s.Equal(Dir, obtained)
+
+	obtained, err = NewFromOSFileModeWithPolicy(os.FileMode(0777)|os.ModeSymlink, policy)
+	s.NoError(err)
+	s.Equal(Symlink, obtained)
+}
@@ -0,0 +1,73 @@
+package filemode
+
+import (
+	"archive/tar"
+	"fmt"
+)
+
+// submodulePAXRecord marks a tar.TypeDir entry, produced by
+// ApplyToTarHeader, as standing in for a git submodule (a gitlink) rather
+// than an actual directory, since the tar format has no Typeflag of its
+// own for that concept.
+const submodulePAXRecord = "GIT.submodule"
+
+// FromTarHeader returns the FileMode equivalent to the type and
+// permissions recorded in the given tar.Header, or an error if the
+// header's Typeflag has no git equivalent.
+func FromTarHeader(h *tar.Header) (FileMode, error) {
+	switch h.Typeflag {
+	case tar.TypeReg, tar.TypeRegA:
+		if h.Mode&0111 != 0 {
+			return Executable, nil
+		}
+
+		return Regular, nil
+	case tar.TypeSymlink:
+		return Symlink, nil
+	case tar.TypeDir:
+		if h.PAXRecords[submodulePAXRecord] == "true" {
+			return Submodule, nil
+		}
+
+		return Dir, nil
+	}
+
+	return Empty, fmt.Errorf("no equivalent file mode: tar type %q", h.Typeflag)
+}
+
+// ApplyToTarHeader sets the Typeflag, Mode and, for submodules, the PAX
+// records of h to match m, mirroring how archive/tar's headerFileInfo
+// derives a header's permission and type from an fs.FileMode. Deprecated
+// is written out as Regular, since tar has no notion of the deprecated
+// group-writable git mode.
+//
+// This is the mapping a "git archive"-style exporter or a "tar to tree"
+// importer needs to move trees in and out of tar archives without
+// reimplementing mode arithmetic.
+func (m FileMode) ApplyToTarHeader(h *tar.Header) error {
+	switch m {
+	case Regular, Deprecated:
+		h.Typeflag = tar.TypeReg
+		h.Mode = 0644
+	case Executable:
+		h.Typeflag = tar.TypeReg
+		h.Mode = 0755
+	case Symlink:
+		h.Typeflag = tar.TypeSymlink
+		h.Mode = 0777
+	case Dir:
+		h.Typeflag = tar.TypeDir
+		h.Mode = 0755
+	case Submodule:
+		h.Typeflag = tar.TypeDir
+		h.Mode = 0755
+		if h.PAXRecords == nil {
+			h.PAXRecords = make(map[string]string, 1)
+		}
+		h.PAXRecords[submodulePAXRecord] = "true"
+	default:
+		return fmt.Errorf("malformed mode (%s)", m)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package filemode
+
+import (
+	"archive/tar"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TarSuite struct {
+	suite.Suite
+}
+
+func TestTarSuite(t *testing.T) {
+	suite.Run(t, new(TarSuite))
+}
+
+func (s *TarSuite) TestApplyToTarHeader() {
+	for _, test := range [...]struct {
+		input        FileMode
+		expectedType byte
+		expectedMode int64
+		errRegExp    string // empty string for nil error
+	}{
+		{Regular, tar.TypeReg, 0644, ""},
+		{Deprecated, tar.TypeReg, 0644, ""},
+		{Executable, tar.TypeReg, 0755, ""},
+		{Symlink, tar.TypeSymlink, 0777, ""},
+		{Dir, tar.TypeDir, 0755, ""},
+		{Submodule, tar.TypeDir, 0755, ""},
+		{Empty, 0, 0, "malformed"},
+	} {
+		h := &tar.Header{}
+		err := test.input.ApplyToTarHeader(h)
+		comment := fmt.Sprintf("input = %s", test.input)
+		if test.errRegExp != "" {
+			s.ErrorContains(err, test.errRegExp, comment)
+			continue
+		}
+
+		s.NoError(err, comment)
+		s.Equal(test.expectedType, h.Typeflag, comment)
+		s.Equal(test.expectedMode, h.Mode, comment)
+	}
+
+	h := &tar.Header{}
+	s.NoError(Submodule.ApplyToTarHeader(h))
+	s.Equal("true", h.PAXRecords[submodulePAXRecord])
+}
+
+func (s *TarSuite) TestFromTarHeader() {
+	for _, test := range [...]struct {
+		header   *tar.Header
+		expected FileMode
+		err      string
+	}{
+		{&tar.Header{Typeflag: tar.TypeReg, Mode: 0644}, Regular, ""},
+		{&tar.Header{Typeflag: tar.TypeReg, Mode: 0755}, Executable, ""},
+		{&tar.Header{Typeflag: tar.TypeSymlink}, Symlink, ""},
+		{&tar.Header{Typeflag: tar.TypeDir}, Dir, ""},
+		{
+			&tar.Header{
+				Typeflag:   tar.TypeDir,
+				PAXRecords: map[string]string{submodulePAXRecord: "true"},
+			},
+			Submodule, "",
+		},
+		{&tar.Header{Typeflag: tar.TypeChar}, Empty, "no equivalent"},
+	} {
+		obtained, err := FromTarHeader(test.header)
+		comment := fmt.Sprintf("header = %+v", test.header)
+		s.Equal(test.expected, obtained, comment)
+		if test.err != "" {
+			s.ErrorContains(err, test.err, comment)
+		} else {
+			s.NoError(err, comment)
+		}
+	}
+}
+
+func (s *TarSuite) TestTarRoundTrip() {
+	for _, mode := range [...]FileMode{Regular, Executable, Symlink, Dir, Submodule} {
+		h := &tar.Header{}
+		s.NoError(mode.ApplyToTarHeader(h))
+
+		obtained, err := FromTarHeader(h)
+		s.NoError(err)
+		s.Equal(mode, obtained, fmt.Sprintf("mode = %s", mode))
+	}
+}